@@ -0,0 +1,50 @@
+package callable
+
+import (
+	"context"
+
+	"firebase.google.com/go/auth"
+)
+
+// Principal describes an authenticated caller, as returned by a
+// TokenVerifier.
+type Principal struct {
+	// UID is the caller's unique identifier, as asserted by the token.
+	UID string
+	// Claims carries any additional claims asserted by the token, keyed by
+	// claim name.
+	Claims map[string]interface{}
+}
+
+// TokenVerifier validates a bearer token extracted from the Authorization
+// header and returns the Principal it identifies. Implementations should
+// return an error for any token that is missing, malformed, expired, or
+// otherwise fails to verify.
+type TokenVerifier interface {
+	Verify(ctx context.Context, bearer string) (Principal, error)
+}
+
+// NoopVerifier is a TokenVerifier that treats the bearer token itself as
+// the caller's UID, without any verification. It exists for local
+// development and tests; never use it against untrusted callers.
+type NoopVerifier struct{}
+
+// Verify implements TokenVerifier.
+func (NoopVerifier) Verify(_ context.Context, bearer string) (Principal, error) {
+	return Principal{UID: bearer}, nil
+}
+
+// FirebaseVerifier is a TokenVerifier backed by Firebase Auth, verifying ID
+// tokens with the given *auth.Client. This is what WithAuth configures.
+type FirebaseVerifier struct {
+	Client *auth.Client
+}
+
+// Verify implements TokenVerifier.
+func (v FirebaseVerifier) Verify(ctx context.Context, bearer string) (Principal, error) {
+	token, err := v.Client.VerifyIDTokenAndCheckRevoked(ctx, bearer)
+	if err != nil {
+		return Principal{}, err
+	}
+	return Principal{UID: token.UID, Claims: token.Claims}, nil
+}
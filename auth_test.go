@@ -0,0 +1,81 @@
+package callable
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoopVerifier(t *testing.T) {
+	p, err := NoopVerifier{}.Verify(context.Background(), "some-token")
+	assert.NoError(t, err)
+	assert.Equal(t, Principal{UID: "some-token"}, p)
+}
+
+func TestCallable_TokenVerifier(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Authorization", "Bearer some-user")
+	w := httptest.NewRecorder()
+	New(&testRequest{}, WithTokenVerifier(NoopVerifier{}, true)).ServeHTTP(w, r)
+	res, body := response(w)
+
+	assert.Equal(t, 200, res.StatusCode)
+	assert.JSONEq(t, `{"data":{"greeting":"Hello World!"}}`, body)
+}
+
+func TestCallable_TokenVerifier_MissingRequired(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	New(&testRequest{}, WithTokenVerifier(NoopVerifier{}, true)).ServeHTTP(w, r)
+	res, body := response(w)
+
+	assert.Equal(t, 401, res.StatusCode)
+	assert.JSONEq(t, `{"error":{"status":"UNAUTHENTICATED","code":16,"message":"invalid ID token: missing Authorization header"}}`, body)
+}
+
+func TestCallable_TokenVerifier_MissingOptional(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	New(&testRequest{}, WithTokenVerifier(NoopVerifier{}, false)).ServeHTTP(w, r)
+	res, body := response(w)
+
+	assert.Equal(t, 200, res.StatusCode)
+	assert.JSONEq(t, `{"data":{"greeting":"Hello World!"}}`, body)
+}
+
+func TestHMACVerifier(t *testing.T) {
+	secret := []byte("test-secret")
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user-1"})
+	signed, err := token.SignedString(secret)
+	assert.NoError(t, err)
+
+	p, err := HMACVerifier{Secret: secret}.Verify(context.Background(), signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", p.UID)
+}
+
+func TestHMACVerifier_WrongSecret(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user-1"})
+	signed, err := token.SignedString([]byte("test-secret"))
+	assert.NoError(t, err)
+
+	_, err = HMACVerifier{Secret: []byte("wrong-secret")}.Verify(context.Background(), signed)
+	assert.Error(t, err)
+}
+
+func TestHMACVerifier_WrongAlgorithm(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": "user-1"})
+	signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+
+	_, err = HMACVerifier{Secret: []byte("test-secret")}.Verify(context.Background(), signed)
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"mime"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"firebase.google.com/go/auth"
@@ -15,10 +16,15 @@ import (
 
 // Callable encapsulates a Firebase 'onCall' request with its handler method.
 type Callable struct {
-	request      Request
-	authClient   *auth.Client
-	authRequired bool
-	logger       *logr.Logger
+	request        Request
+	verifier       TokenVerifier
+	authRequired   bool
+	logger         *logr.Logger
+	middleware     []func(http.Handler) http.Handler
+	callMiddleware []CallMiddleware
+	handler        http.Handler
+	cors           *CORSOptions
+	observer       Observer
 }
 
 // Request represents the call request data and provides a method to handle it.
@@ -39,6 +45,10 @@ type Call struct {
 	// IID is the Firebase Instance ID token (the FCM registration token).
 	// Can be used to target push notifications.
 	IID string
+	// Claims carries any additional claims asserted by the caller's token,
+	// as returned by the configured TokenVerifier. Empty when no verifier
+	// is configured or the call is unauthenticated.
+	Claims map[string]interface{}
 }
 
 // Option configures a Callable.
@@ -47,12 +57,12 @@ type Option interface {
 }
 
 type authOption struct {
-	client   *auth.Client
+	verifier TokenVerifier
 	required bool
 }
 
 func (o authOption) config(c *Callable) {
-	c.authClient = o.client
+	c.verifier = o.verifier
 	c.authRequired = o.required
 }
 
@@ -77,17 +87,37 @@ func New(request Request, opts ...Option) *Callable {
 	for _, opt := range opts {
 		opt.config(c)
 	}
+
+	var h http.Handler = http.HandlerFunc(c.serveHTTP)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	c.handler = h
+
 	return c
 }
 
 // WithAuth specifies the Firebase Auth client that will be used to validate
 // ID tokens. The `required` flag controls whether a valid ID token is
-// required or not.
+// required or not. This is a convenience wrapper around WithTokenVerifier
+// for the common case of a Firebase Callable deployment.
 func WithAuth(client *auth.Client, required bool) Option {
 	if client == nil {
 		panic("nil *auth.Client")
 	}
-	return authOption{client, required}
+	return authOption{FirebaseVerifier{client}, required}
+}
+
+// WithTokenVerifier specifies the TokenVerifier that will be used to
+// validate bearer tokens, allowing deployments that aren't backed by
+// Firebase Auth (e.g. a generic OIDC provider, or a self-issued JWT) to
+// plug in their own. The `required` flag controls whether a valid token is
+// required or not.
+func WithTokenVerifier(verifier TokenVerifier, required bool) Option {
+	if verifier == nil {
+		panic("nil TokenVerifier")
+	}
+	return authOption{verifier, required}
 }
 
 // WithLogger adds a logger that will be used to log errors.
@@ -95,23 +125,79 @@ func WithLogger(logger logr.Logger) Option {
 	return loggerOption{logger}
 }
 
+type middlewareOption struct {
+	mw []func(http.Handler) http.Handler
+}
+
+func (o middlewareOption) config(c *Callable) {
+	c.middleware = append(c.middleware, o.mw...)
+}
+
+// WithMiddleware wraps the Callable's http.Handler with the given
+// middleware. Middleware is applied in the order given, so the first
+// middleware is outermost and sees the request before the others. Use this
+// for cross-cutting concerns - tracing, metrics, rate limiting - that need
+// to run before a token is validated or the body is decoded.
+func WithMiddleware(mw ...func(http.Handler) http.Handler) Option {
+	return middlewareOption{mw}
+}
+
+type callMiddlewareOption struct {
+	mw []CallMiddleware
+}
+
+func (o callMiddlewareOption) config(c *Callable) {
+	c.callMiddleware = append(c.callMiddleware, o.mw...)
+}
+
+// WithCallMiddleware wraps the decoded Request's Handle invocation with the
+// given CallMiddleware. Unlike WithMiddleware, these run after the call has
+// been authenticated and decoded, and have access to the Call struct.
+// Middleware is applied in the order given, so the first middleware is
+// outermost.
+func WithCallMiddleware(mw ...CallMiddleware) Option {
+	return callMiddlewareOption{mw}
+}
+
+type corsOption struct {
+	opts CORSOptions
+}
+
+func (o corsOption) config(c *Callable) {
+	opts := o.opts
+	c.cors = &opts
+}
+
+// WithCORS restricts cross-origin requests to an explicit allow-list,
+// instead of the default behavior of reflecting back any Origin header.
+// Preflight requests from an origin that isn't allowed receive a 403.
+func WithCORS(opts CORSOptions) Option {
+	return corsOption{opts}
+}
+
+// ServeHTTP implements http.Handler, dispatching through any middleware
+// configured with WithMiddleware before handling the request.
 func (c *Callable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.handler.ServeHTTP(w, r)
+}
+
+func (c *Callable) serveHTTP(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodOptions:
 		c.handleCorsPreflight(w, r)
 	case http.MethodPost:
 		if err := c.handlePost(w, r); err != nil {
-			var cerr callError
-			if !errors.As(err, &cerr) {
+			var rerr RenderableError
+			if !errors.As(err, &rerr) {
 				if errors.Is(err, context.Canceled) {
-					cerr = newError(Cancelled, "%s", err.Error())
+					rerr = newError(Cancelled, "%s", err.Error())
 				} else if errors.Is(err, context.DeadlineExceeded) {
-					cerr = newError(DeadlineExceeded, "%s", err.Error())
+					rerr = newError(DeadlineExceeded, "%s", err.Error())
 				} else {
-					cerr = newError(Internal, "%s", err.Error())
+					rerr = newError(Internal, "%s", err.Error())
 				}
 			}
-			cerr.write(w)
+			rerr.Render(w)
 		}
 	default:
 		http.Error(w, "", http.StatusMethodNotAllowed)
@@ -121,18 +207,48 @@ func (c *Callable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (c *Callable) handleCorsPreflight(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("Vary", "Origin")
 	w.Header().Add("Vary", "Access-Control-Request-Headers")
-	w.Header().Add("Access-Control-Allow-Methods", "POST")
+
+	origin := r.Header.Get("Origin")
+	if origin != "" && c.cors != nil && !c.cors.allowsOrigin(origin) {
+		http.Error(w, "", http.StatusForbidden)
+		return
+	}
+
+	methods := "POST"
+	if c.cors != nil && len(c.cors.AllowedMethods) > 0 {
+		methods = strings.Join(c.cors.AllowedMethods, ", ")
+	}
+	w.Header().Add("Access-Control-Allow-Methods", methods)
+
 	if r.Header.Get("Access-Control-Request-Headers") != "" {
-		w.Header().Add("Access-Control-Allow-Headers", "*")
+		headers := "*"
+		if c.cors != nil && len(c.cors.AllowedHeaders) > 0 {
+			headers = strings.Join(c.cors.AllowedHeaders, ", ")
+		}
+		w.Header().Add("Access-Control-Allow-Headers", headers)
 	}
-	if origin := r.Header.Get("Origin"); origin != "" {
+
+	if origin != "" {
 		w.Header().Add("Access-Control-Allow-Origin", origin)
+		c.addCORSExtras(w)
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (c *Callable) addCORSExtras(w http.ResponseWriter) {
+	if c.cors == nil {
+		return
+	}
+	if c.cors.AllowCredentials {
+		w.Header().Add("Access-Control-Allow-Credentials", "true")
+	}
+	if c.cors.MaxAge > 0 {
+		w.Header().Add("Access-Control-Max-Age", strconv.Itoa(int(c.cors.MaxAge.Seconds())))
+	}
+}
+
 func (c *Callable) handlePost(w http.ResponseWriter, r *http.Request) error {
-	token, err := c.validateToken(r)
+	principal, err := c.validateToken(r)
 	if err != nil {
 		return Error(Unauthenticated, "invalid ID token: %v", err)
 	}
@@ -155,8 +271,9 @@ func (c *Callable) handlePost(w http.ResponseWriter, r *http.Request) error {
 
 	// send CORS headers
 	w.Header().Add("Vary", "Origin")
-	if origin := r.Header.Get("Origin"); origin != "" {
+	if origin := r.Header.Get("Origin"); origin != "" && (c.cors == nil || c.cors.allowsOrigin(origin)) {
 		w.Header().Add("Access-Control-Allow-Origin", origin)
+		c.addCORSExtras(w)
 	}
 
 	var payload = struct {
@@ -173,15 +290,37 @@ func (c *Callable) handlePost(w http.ResponseWriter, r *http.Request) error {
 	} else {
 		logger = *c.logger
 	}
+	if id, ok := RequestIDFromContext(r.Context()); ok {
+		logger = logger.WithValues("requestId", id)
+	}
 
 	call := Call{IID: r.Header.Get("Firebase-Instance-ID-Token")}
-	if token != nil {
-		call.UID = token.UID
+	if principal != nil {
+		call.UID = principal.UID
+		call.Claims = principal.Claims
+	}
+
+	ctx := context.WithValue(r.Context(), callContextKey{}, call)
+	var end EndFunc
+	if c.observer != nil {
+		ctx, end = c.observer.StartCall(ctx, requestMethod(c.request))
+	}
+
+	if streaming, ok := c.request.(StreamingRequest); ok && wantsStream(r) {
+		return c.handleStream(ctx, w, call, streaming, logger, end)
+	}
+
+	handle := HandleFunc(c.request.Handle)
+	for i := len(c.callMiddleware) - 1; i >= 0; i-- {
+		handle = c.callMiddleware[i](handle)
 	}
 
-	result, err := c.request.Handle(r.Context(), call)
+	result, err := handle(ctx, call)
+	if end != nil {
+		end(statusCodeFor(err), err)
+	}
 	if err != nil {
-		logger.Error(err, "callable returned error", err)
+		logger.Error(err, "callable returned error")
 		return err
 	}
 
@@ -193,22 +332,25 @@ func (c *Callable) handlePost(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
-func (c *Callable) validateToken(r *http.Request) (*auth.Token, error) {
-	if c.authClient == nil {
+func (c *Callable) validateToken(r *http.Request) (*Principal, error) {
+	if c.verifier == nil {
 		return nil, nil
 	}
 
 	parts := strings.Fields(r.Header.Get("Authorization"))
-	if len(parts) == 0 && c.authRequired {
-		return nil, fmt.Errorf("missing Authorization header")
+	if len(parts) == 0 {
+		if c.authRequired {
+			return nil, fmt.Errorf("missing Authorization header")
+		}
+		return nil, nil
 	}
 	if len(parts) != 2 || parts[0] != "Bearer" {
 		return nil, fmt.Errorf("unsupported Authorization header")
 	}
 
-	token, err := c.authClient.VerifyIDTokenAndCheckRevoked(r.Context(), parts[1])
+	principal, err := c.verifier.Verify(r.Context(), parts[1])
 	if err != nil {
 		return nil, err
 	}
-	return token, nil
+	return &principal, nil
 }
@@ -25,6 +25,9 @@ func (r *testRequest) Handle(ctx context.Context, call Call) (interface{}, error
 	if r.Who == "error" {
 		return nil, errors.New("some error")
 	}
+	if r.Who == "custom-error" {
+		return nil, customRenderableError{}
+	}
 	if call.IID != "" {
 		return testResponse{"IID: " + call.IID}, nil
 	}
@@ -65,7 +68,7 @@ func TestCallable_BadContent(t *testing.T) {
 	res, body := response(w)
 
 	assert.Equal(t, 400, res.StatusCode)
-	assert.JSONEq(t, `{"error":{"status":"INVALID_ARGUMENT","message":"missing content type"}}`, body)
+	assert.JSONEq(t, `{"error":{"status":"INVALID_ARGUMENT","code":3,"message":"missing content type"}}`, body)
 }
 
 func TestCallable_BadContent_TextPlain(t *testing.T) {
@@ -76,7 +79,7 @@ func TestCallable_BadContent_TextPlain(t *testing.T) {
 	res, body := response(w)
 
 	assert.Equal(t, 400, res.StatusCode)
-	assert.JSONEq(t, `{"error":{"status":"INVALID_ARGUMENT","message":"unsupported content type"}}`, body)
+	assert.JSONEq(t, `{"error":{"status":"INVALID_ARGUMENT","code":3,"message":"unsupported content type"}}`, body)
 }
 
 func TestCallable_BadContent_Charset(t *testing.T) {
@@ -87,7 +90,7 @@ func TestCallable_BadContent_Charset(t *testing.T) {
 	res, body := response(w)
 
 	assert.Equal(t, 400, res.StatusCode)
-	assert.JSONEq(t, `{"error":{"status":"INVALID_ARGUMENT","message":"unsupported encoding"}}`, body)
+	assert.JSONEq(t, `{"error":{"status":"INVALID_ARGUMENT","code":3,"message":"unsupported encoding"}}`, body)
 }
 
 func TestCallable_Success(t *testing.T) {
@@ -113,7 +116,7 @@ func TestCallable_Error(t *testing.T) {
 	assert.Equal(t, 404, res.StatusCode)
 	assert.Equal(t, "application/json; charset=utf-8", res.Header.Get("Content-Type"))
 	assert.Equal(t, []string{"Origin"}, res.Header.Values("Vary"))
-	assert.JSONEq(t, `{"error":{"status":"NOT_FOUND","message":"nobody to greet"}}`, body)
+	assert.JSONEq(t, `{"error":{"status":"NOT_FOUND","code":5,"message":"nobody to greet"}}`, body)
 }
 
 func TestCallable_Error_Internal(t *testing.T) {
@@ -126,7 +129,7 @@ func TestCallable_Error_Internal(t *testing.T) {
 	assert.Equal(t, 500, res.StatusCode)
 	assert.Equal(t, "application/json; charset=utf-8", res.Header.Get("Content-Type"))
 	assert.Equal(t, []string{"Origin"}, res.Header.Values("Vary"))
-	assert.JSONEq(t, `{"error":{"status":"INTERNAL","message":"some error"}}`, body)
+	assert.JSONEq(t, `{"error":{"status":"INTERNAL","code":13,"message":"some error"}}`, body)
 }
 
 func TestCallable_IID(t *testing.T) {
@@ -0,0 +1,39 @@
+package callable
+
+import (
+	"strings"
+	"time"
+)
+
+// CORSOptions configures the cross-origin allow-list used by WithCORS.
+type CORSOptions struct {
+	// AllowedOrigins lists the origins allowed to make cross-origin
+	// requests. Entries may be an exact origin (e.g. "https://example.com")
+	// or a wildcard suffix (e.g. "*.example.com") matching any subdomain.
+	AllowedOrigins []string
+	// AllowedHeaders, if set, is sent as Access-Control-Allow-Headers in
+	// response to a preflight request that carries
+	// Access-Control-Request-Headers. Defaults to "*" when unset.
+	AllowedHeaders []string
+	// AllowedMethods, if set, is sent as Access-Control-Allow-Methods in
+	// response to a preflight request. Defaults to "POST" when unset.
+	AllowedMethods []string
+	// AllowCredentials sends Access-Control-Allow-Credentials: true for
+	// matching origins.
+	AllowCredentials bool
+	// MaxAge, when positive, sends Access-Control-Max-Age for matching
+	// origins.
+	MaxAge time.Duration
+}
+
+func (o CORSOptions) allowsOrigin(origin string) bool {
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == origin {
+			return true
+		}
+		if strings.HasPrefix(allowed, "*.") && strings.HasSuffix(origin, allowed[1:]) {
+			return true
+		}
+	}
+	return false
+}
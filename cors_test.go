@@ -0,0 +1,136 @@
+package callable
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallable_CORS_AllowList_Match(t *testing.T) {
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	opts := CORSOptions{AllowedOrigins: []string{"https://example.com"}, AllowCredentials: true}
+	New(&testRequest{}, WithCORS(opts)).ServeHTTP(w, r)
+	res, body := response(w)
+
+	assert.Equal(t, 204, res.StatusCode)
+	assert.Equal(t, []string{"https://example.com"}, res.Header.Values("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", res.Header.Get("Access-Control-Allow-Credentials"))
+	assert.Empty(t, body)
+}
+
+func TestCallable_CORS_AllowList_WildcardMatch(t *testing.T) {
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://foo.example.com")
+	w := httptest.NewRecorder()
+	opts := CORSOptions{AllowedOrigins: []string{"*.example.com"}}
+	New(&testRequest{}, WithCORS(opts)).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, 204, res.StatusCode)
+	assert.Equal(t, []string{"https://foo.example.com"}, res.Header.Values("Access-Control-Allow-Origin"))
+}
+
+func TestCallable_CORS_AllowList_Rejected(t *testing.T) {
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://evil.com")
+	w := httptest.NewRecorder()
+	opts := CORSOptions{AllowedOrigins: []string{"https://example.com"}}
+	New(&testRequest{}, WithCORS(opts)).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, 403, res.StatusCode)
+	assert.Empty(t, res.Header.Values("Access-Control-Allow-Origin"))
+}
+
+func TestCallable_CORS_Preflight_AllowedHeaders(t *testing.T) {
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	r.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	w := httptest.NewRecorder()
+	opts := CORSOptions{AllowedOrigins: []string{"https://example.com"}, AllowedHeaders: []string{"X-Custom", "Authorization"}}
+	New(&testRequest{}, WithCORS(opts)).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, 204, res.StatusCode)
+	assert.Equal(t, "X-Custom, Authorization", res.Header.Get("Access-Control-Allow-Headers"))
+}
+
+func TestCallable_CORS_Preflight_AllowedHeaders_Default(t *testing.T) {
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	r.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	w := httptest.NewRecorder()
+	opts := CORSOptions{AllowedOrigins: []string{"https://example.com"}}
+	New(&testRequest{}, WithCORS(opts)).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, 204, res.StatusCode)
+	assert.Equal(t, "*", res.Header.Get("Access-Control-Allow-Headers"))
+}
+
+func TestCallable_CORS_Preflight_AllowedMethods(t *testing.T) {
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	opts := CORSOptions{AllowedOrigins: []string{"https://example.com"}, AllowedMethods: []string{"POST", "GET"}}
+	New(&testRequest{}, WithCORS(opts)).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, 204, res.StatusCode)
+	assert.Equal(t, "POST, GET", res.Header.Get("Access-Control-Allow-Methods"))
+}
+
+func TestCallable_CORS_Preflight_AllowedMethods_Default(t *testing.T) {
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	opts := CORSOptions{AllowedOrigins: []string{"https://example.com"}}
+	New(&testRequest{}, WithCORS(opts)).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, 204, res.StatusCode)
+	assert.Equal(t, "POST", res.Header.Get("Access-Control-Allow-Methods"))
+}
+
+func TestCallable_CORS_Preflight_MaxAge(t *testing.T) {
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	opts := CORSOptions{AllowedOrigins: []string{"https://example.com"}, MaxAge: 10 * time.Minute}
+	New(&testRequest{}, WithCORS(opts)).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, 204, res.StatusCode)
+	assert.Equal(t, "600", res.Header.Get("Access-Control-Max-Age"))
+}
+
+func TestCallable_CORS_Preflight_MaxAge_Unset(t *testing.T) {
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	opts := CORSOptions{AllowedOrigins: []string{"https://example.com"}}
+	New(&testRequest{}, WithCORS(opts)).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, 204, res.StatusCode)
+	assert.Empty(t, res.Header.Values("Access-Control-Max-Age"))
+}
+
+func TestCallable_CORS_AllowList_Post(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Origin", "https://evil.com")
+	w := httptest.NewRecorder()
+	opts := CORSOptions{AllowedOrigins: []string{"https://example.com"}}
+	New(&testRequest{}, WithCORS(opts)).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Empty(t, res.Header.Values("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,62 @@
+package callable
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// HandleFunc matches the signature of Request.Handle, allowing a bare
+// function to be wrapped by a CallMiddleware.
+type HandleFunc func(context.Context, Call) (interface{}, error)
+
+// CallMiddleware wraps a HandleFunc, giving cross-cutting concerns access
+// to the decoded Call before and after the underlying Request is handled.
+// See WithCallMiddleware.
+type CallMiddleware func(next HandleFunc) HandleFunc
+
+// RequestIDHeader is the HTTP header used to propagate the request ID, both
+// on the incoming request and on the response.
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDContextKey struct{}
+
+// RequestIDFromContext returns the request ID stored on ctx by
+// RequestIDMiddleware, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDContextKey{}).(string)
+	return id, ok
+}
+
+// RequestIDOptions configures RequestIDMiddleware.
+type RequestIDOptions struct {
+	// LegacyHeader, when set, is consulted when RequestIDHeader is absent
+	// from the incoming request (e.g. "X-Smallstep-Id").
+	LegacyHeader string
+}
+
+// RequestIDMiddleware returns http.Handler middleware, for use with
+// WithMiddleware, that propagates a request ID across the call. It honors
+// RequestIDHeader on the incoming request, falling back to
+// opts.LegacyHeader when set, and generates a fresh UUID when neither is
+// present. The ID is stored on the request context, retrievable with
+// RequestIDFromContext, and echoed back via the RequestIDHeader response
+// header.
+func RequestIDMiddleware(opts RequestIDOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if id == "" && opts.LegacyHeader != "" {
+				id = r.Header.Get(opts.LegacyHeader)
+			}
+			if id == "" {
+				id = uuid.NewString()
+			}
+
+			w.Header().Set(RequestIDHeader, id)
+			ctx := context.WithValue(r.Context(), requestIDContextKey{}, id)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package callable
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallable_Middleware(t *testing.T) {
+	var seen string
+	mw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen = "before"
+			next.ServeHTTP(w, r)
+			seen += ",after"
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	New(&testRequest{}, WithMiddleware(mw)).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "before,after", seen)
+}
+
+func TestCallable_CallMiddleware(t *testing.T) {
+	var sawUID string
+	mw := func(next HandleFunc) HandleFunc {
+		return func(ctx context.Context, call Call) (interface{}, error) {
+			sawUID = call.UID
+			return next(ctx, call)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	New(&testRequest{}, WithCallMiddleware(mw)).ServeHTTP(w, r)
+	res, body := response(w)
+
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "", sawUID)
+	assert.JSONEq(t, `{"data":{"greeting":"Hello World!"}}`, body)
+}
+
+func TestRequestIDMiddleware_Generated(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	New(&testRequest{}, WithMiddleware(RequestIDMiddleware(RequestIDOptions{}))).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, 200, res.StatusCode)
+	assert.NotEmpty(t, res.Header.Get(RequestIDHeader))
+}
+
+func TestRequestIDMiddleware_Propagated(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(RequestIDHeader, "req-123")
+	w := httptest.NewRecorder()
+	New(&testRequest{}, WithMiddleware(RequestIDMiddleware(RequestIDOptions{}))).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, "req-123", res.Header.Get(RequestIDHeader))
+}
+
+func TestRequestIDMiddleware_LegacyHeader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Smallstep-Id", "legacy-456")
+	w := httptest.NewRecorder()
+	opts := RequestIDOptions{LegacyHeader: "X-Smallstep-Id"}
+	New(&testRequest{}, WithMiddleware(RequestIDMiddleware(opts))).ServeHTTP(w, r)
+	res, _ := response(w)
+
+	assert.Equal(t, "legacy-456", res.Header.Get(RequestIDHeader))
+}
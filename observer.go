@@ -0,0 +1,61 @@
+package callable
+
+import (
+	"context"
+	"reflect"
+)
+
+// EndFunc concludes the call span started by Observer.StartCall. statusCode
+// is the HTTP status code for the call's outcome: 200 on success, otherwise
+// the status of the error response. For a streamed call that fails it is the
+// error's status, although the stream itself was sent with 200; a
+// RenderableError not created by this package is reported as 500. err is
+// the error returned by the Request (nil on success).
+type EndFunc func(statusCode int, err error)
+
+// Observer hooks into the lifecycle of a call for cross-cutting
+// observability concerns - metrics, tracing, access logs - without callers
+// having to re-wrap ServeHTTP themselves. See WithObserver.
+type Observer interface {
+	// StartCall is invoked before the Request's Handle (or HandleStream) is
+	// called, with method set to the Request's type name. The returned
+	// context replaces the one passed to Handle, so any span or values it
+	// carries are visible to the callable and anything it calls. The
+	// returned EndFunc must be called exactly once when the call completes.
+	StartCall(ctx context.Context, method string) (context.Context, EndFunc)
+}
+
+type observerOption struct {
+	observer Observer
+}
+
+func (o observerOption) config(c *Callable) {
+	c.observer = o.observer
+}
+
+// WithObserver adds an Observer that wraps every call with StartCall/
+// EndFunc, for deployments that want metrics, tracing spans, or access
+// logs without each handler re-implementing them. See PrometheusObserver
+// and OTelObserver for built-in implementations.
+func WithObserver(observer Observer) Option {
+	return observerOption{observer}
+}
+
+type callContextKey struct{}
+
+// CallFromContext returns the Call associated with ctx, if any. The context
+// passed to Observer.StartCall carries it, so an Observer that wants to
+// attach the caller's UID or IID to a span or log line can retrieve it
+// without Call being threaded through its own plumbing.
+func CallFromContext(ctx context.Context) (Call, bool) {
+	call, ok := ctx.Value(callContextKey{}).(Call)
+	return call, ok
+}
+
+func requestMethod(request Request) string {
+	t := reflect.TypeOf(request)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
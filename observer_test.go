@@ -0,0 +1,99 @@
+package callable
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type testObserver struct {
+	method     string
+	sawUID     string
+	statusCode int
+	err        error
+}
+
+func (o *testObserver) StartCall(ctx context.Context, method string) (context.Context, EndFunc) {
+	o.method = method
+	if call, ok := CallFromContext(ctx); ok {
+		o.sawUID = call.UID
+	}
+	return ctx, func(statusCode int, err error) {
+		o.statusCode = statusCode
+		o.err = err
+	}
+}
+
+func TestCallable_Observer_Success(t *testing.T) {
+	obs := &testObserver{}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	New(&testRequest{}, WithObserver(obs)).ServeHTTP(w, r)
+
+	assert.Equal(t, "testRequest", obs.method)
+	assert.Equal(t, 200, obs.statusCode)
+	assert.NoError(t, obs.err)
+}
+
+func TestCallable_Observer_Error(t *testing.T) {
+	obs := &testObserver{}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	New(&testRequest{}, WithObserver(obs)).ServeHTTP(w, r)
+
+	assert.Equal(t, 404, obs.statusCode)
+	assert.Error(t, obs.err)
+}
+
+func TestPrometheusObserver(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	obs := NewPrometheusObserver(reg)
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	New(&testRequest{}, WithObserver(obs)).ServeHTTP(w, r)
+
+	families, err := reg.Gather()
+	assert.NoError(t, err)
+
+	var found bool
+	for _, f := range families {
+		if f.GetName() != "callable_requests_total" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "handler" && l.GetValue() == "testRequest" {
+					found = true
+					assert.Equal(t, float64(1), m.GetCounter().GetValue())
+				}
+			}
+		}
+	}
+	assert.True(t, found, "expected a callable_requests_total sample for testRequest")
+}
+
+func TestOTelObserver(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+	obs := NewOTelObserver(provider)
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	New(&testRequest{}, WithObserver(obs)).ServeHTTP(w, r)
+
+	spans := sr.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "testRequest", spans[0].Name())
+}
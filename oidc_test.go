@@ -0,0 +1,70 @@
+package callable
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeIDToken struct {
+	subject string
+	claims  map[string]interface{}
+	err     error
+}
+
+func (f fakeIDToken) Subject() string { return f.subject }
+
+func (f fakeIDToken) Claims(v interface{}) error {
+	if f.err != nil {
+		return f.err
+	}
+	data, err := json.Marshal(f.claims)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+type fakeIDTokenVerifier struct {
+	token *fakeIDToken
+	err   error
+}
+
+func (f fakeIDTokenVerifier) Verify(ctx context.Context, rawIDToken string) (verifiedToken, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return *f.token, nil
+}
+
+func TestOIDCVerifier_Verify(t *testing.T) {
+	v := &OIDCVerifier{verifier: fakeIDTokenVerifier{token: &fakeIDToken{
+		subject: "user-1",
+		claims:  map[string]interface{}{"email": "user@example.com"},
+	}}}
+
+	p, err := v.Verify(context.Background(), "some-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", p.UID)
+	assert.Equal(t, "user@example.com", p.Claims["email"])
+}
+
+func TestOIDCVerifier_Verify_InvalidToken(t *testing.T) {
+	v := &OIDCVerifier{verifier: fakeIDTokenVerifier{err: errors.New("oidc: token is expired")}}
+
+	_, err := v.Verify(context.Background(), "some-token")
+	assert.EqualError(t, err, "oidc: token is expired")
+}
+
+func TestOIDCVerifier_Verify_ClaimsError(t *testing.T) {
+	v := &OIDCVerifier{verifier: fakeIDTokenVerifier{token: &fakeIDToken{
+		subject: "user-1",
+		err:     errors.New("oidc: claims not set"),
+	}}}
+
+	_, err := v.Verify(context.Background(), "some-token")
+	assert.EqualError(t, err, "oidc: claims not set")
+}
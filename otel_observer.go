@@ -0,0 +1,47 @@
+package callable
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OTelObserver is an Observer that starts an OpenTelemetry span per call,
+// attaching the caller's UID/IID as span attributes when present and
+// mapping the call's outcome to an OTel status.
+type OTelObserver struct {
+	Tracer trace.Tracer
+}
+
+// NewOTelObserver creates an OTelObserver using the given tracer provider's
+// tracer, named after this package.
+func NewOTelObserver(provider trace.TracerProvider) *OTelObserver {
+	return &OTelObserver{Tracer: provider.Tracer("github.com/lonebits/callable")}
+}
+
+// StartCall implements Observer.
+func (o *OTelObserver) StartCall(ctx context.Context, method string) (context.Context, EndFunc) {
+	ctx, span := o.Tracer.Start(ctx, method)
+
+	if call, ok := CallFromContext(ctx); ok {
+		if call.UID != "" {
+			span.SetAttributes(attribute.String("callable.uid", call.UID))
+		}
+		if call.IID != "" {
+			span.SetAttributes(attribute.String("callable.iid", call.IID))
+		}
+	}
+
+	return ctx, func(statusCode int, err error) {
+		span.SetAttributes(attribute.Int("callable.status_code", statusCode))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,44 @@
+package callable
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusObserver is an Observer that records a request counter labeled
+// by handler and status code, and a latency histogram labeled by handler.
+type PrometheusObserver struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+// NewPrometheusObserver creates a PrometheusObserver and registers its
+// collectors with reg. Use prometheus.DefaultRegisterer for the global
+// registry.
+func NewPrometheusObserver(reg prometheus.Registerer) *PrometheusObserver {
+	o := &PrometheusObserver{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "callable_requests_total",
+			Help: "Total number of callable requests, by handler and status code.",
+		}, []string{"handler", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "callable_request_duration_seconds",
+			Help:    "Callable request latency in seconds, by handler.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"handler"}),
+	}
+	reg.MustRegister(o.requests, o.duration)
+	return o
+}
+
+// StartCall implements Observer.
+func (o *PrometheusObserver) StartCall(ctx context.Context, method string) (context.Context, EndFunc) {
+	start := time.Now()
+	return ctx, func(statusCode int, _ error) {
+		o.requests.WithLabelValues(method, strconv.Itoa(statusCode)).Inc()
+		o.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	}
+}
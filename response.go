@@ -1,10 +1,14 @@
 package callable
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // StatusCode distinguishes between different error causes.
@@ -35,44 +39,94 @@ func Error(code StatusCode, format string, a ...interface{}) error {
 	return newError(code, format, a...)
 }
 
+// ErrorWithDetails creates a new callable error carrying arbitrary,
+// JSON-marshalable details alongside the status and message, mirroring the
+// `error.details` field of the Google API error model.
+func ErrorWithDetails(code StatusCode, details []interface{}, format string, a ...interface{}) error {
+	e := newError(code, format, a...)
+	e.details = details
+	return e
+}
+
+// WithRetryAfter attaches retry metadata to an error created by Error or
+// ErrorWithDetails. When the error's status is ResourceExhausted or
+// Unavailable, ServeHTTP emits it as the Retry-After response header. Errors
+// not created by this package are returned unchanged.
+func WithRetryAfter(err error, d time.Duration) error {
+	if cerr, ok := err.(callError); ok {
+		cerr.retryAfter = d
+		return cerr
+	}
+	return err
+}
+
+// WithCause attaches an underlying cause to an error created by Error or
+// ErrorWithDetails, retrievable via errors.Unwrap. Errors not created by
+// this package are returned unchanged.
+func WithCause(err error, cause error) error {
+	if cerr, ok := err.(callError); ok {
+		cerr.cause = cause
+		return cerr
+	}
+	return err
+}
+
 func newError(code StatusCode, format string, a ...interface{}) callError {
 	s, ok := statuses[code]
 	if !ok {
 		s = statuses[Internal]
 	}
 	return callError{
-		status:  s.status,
-		code:    s.code,
-		message: fmt.Sprintf(format, a...),
+		status:   s.status,
+		code:     s.code,
+		grpcCode: s.grpcCode,
+		message:  fmt.Sprintf(format, a...),
 	}
 }
 
 var statuses = map[StatusCode]struct {
-	status string
-	code   int
+	status   string
+	code     int
+	grpcCode int
 }{
-	InvalidArgument:    {"INVALID_ARGUMENT", 400},
-	FailedPrecondition: {"FAILED_PRECONDITION", 400},
-	OutOfRange:         {"OUT_OF_RANGE", 400},
-	Unauthenticated:    {"UNAUTHENTICATED", 401},
-	PermissionDenied:   {"PERMISSION_DENIED", 403},
-	NotFound:           {"NOT_FOUND", 404},
-	Aborted:            {"ABORTED", 409},
-	AlreadyExists:      {"ALREADY_EXISTS", 409},
-	ResourceExhausted:  {"RESOURCE_EXHAUSTED", 429},
-	Cancelled:          {"CANCELLED", 499},
-	DataLoss:           {"DATA_LOSS", 500},
-	Unknown:            {"UNKNOWN", 500},
-	Internal:           {"INTERNAL", 500},
-	NotImplemented:     {"NOT_IMPLEMENTED", 501},
-	Unavailable:        {"UNAVAILABLE", 503},
-	DeadlineExceeded:   {"DEADLINE_EXCEEDED", 504},
+	InvalidArgument:    {"INVALID_ARGUMENT", 400, 3},
+	FailedPrecondition: {"FAILED_PRECONDITION", 400, 9},
+	OutOfRange:         {"OUT_OF_RANGE", 400, 11},
+	Unauthenticated:    {"UNAUTHENTICATED", 401, 16},
+	PermissionDenied:   {"PERMISSION_DENIED", 403, 7},
+	NotFound:           {"NOT_FOUND", 404, 5},
+	Aborted:            {"ABORTED", 409, 10},
+	AlreadyExists:      {"ALREADY_EXISTS", 409, 6},
+	ResourceExhausted:  {"RESOURCE_EXHAUSTED", 429, 8},
+	Cancelled:          {"CANCELLED", 499, 1},
+	DataLoss:           {"DATA_LOSS", 500, 15},
+	Unknown:            {"UNKNOWN", 500, 2},
+	Internal:           {"INTERNAL", 500, 13},
+	NotImplemented:     {"NOT_IMPLEMENTED", 501, 12},
+	Unavailable:        {"UNAVAILABLE", 503, 14},
+	DeadlineExceeded:   {"DEADLINE_EXCEEDED", 504, 4},
+}
+
+// RenderableError is implemented by errors that know how to render
+// themselves as a Callable HTTP response. ServeHTTP recognizes any error
+// satisfying this interface - including the package's own errors created
+// via Error and ErrorWithDetails - and defers to it instead of wrapping the
+// error as an INTERNAL status. In a streamed response the terminal error
+// frame keeps only the package's own errors; any other error is sent as
+// INTERNAL.
+type RenderableError interface {
+	error
+	Render(w http.ResponseWriter)
 }
 
 type callError struct {
-	status  string
-	code    int
-	message string
+	status     string
+	code       int
+	grpcCode   int
+	message    string
+	details    []interface{}
+	retryAfter time.Duration
+	cause      error
 }
 
 func (e callError) Error() string {
@@ -83,19 +137,55 @@ func (e callError) Error() string {
 	return str
 }
 
-func (e callError) write(w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(e.code)
+// Unwrap returns the cause attached with WithCause, if any.
+func (e callError) Unwrap() error {
+	return e.cause
+}
 
-	data := errorResponse{errorData{
+func (e callError) payload() errorResponse {
+	return errorResponse{errorData{
 		Status:  e.status,
+		Code:    e.grpcCode,
 		Message: e.message,
+		Details: e.details,
 	}}
-	if err := json.NewEncoder(w).Encode(data); err != nil {
+}
+
+// Render implements RenderableError.
+func (e callError) Render(w http.ResponseWriter) {
+	if e.retryAfter > 0 && (e.code == statuses[ResourceExhausted].code || e.code == statuses[Unavailable].code) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(e.retryAfter.Seconds())))
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(e.code)
+
+	if err := json.NewEncoder(w).Encode(e.payload()); err != nil {
 		log.Printf("failed to write response: %v", err)
 	}
 }
 
+// statusCodeFor returns the HTTP status code for err, converting context and
+// generic errors the same way ServeHTTP does. A RenderableError that was not
+// created by this package is reported as 500, whatever status it renders.
+// The Callable passes the result to an Observer's EndFunc to record the
+// call's outcome.
+func statusCodeFor(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var cerr callError
+	if errors.As(err, &cerr) {
+		return cerr.code
+	}
+	if errors.Is(err, context.Canceled) {
+		return statuses[Cancelled].code
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return statuses[DeadlineExceeded].code
+	}
+	return statuses[Internal].code
+}
+
 type dataResponse struct {
 	Data interface{} `json:"data"`
 }
@@ -105,6 +195,8 @@ type errorResponse struct {
 }
 
 type errorData struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	Status  string        `json:"status"`
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Details []interface{} `json:"details,omitempty"`
 }
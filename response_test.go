@@ -1,12 +1,14 @@
 package callable
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func response(w *httptest.ResponseRecorder) (*http.Response, string) {
@@ -30,10 +32,71 @@ func TestCallError_write(t *testing.T) {
 	err := newError(Unauthenticated, "test message with %s", "param")
 
 	w := httptest.NewRecorder()
-	err.write(w)
+	err.Render(w)
 	res, body := response(w)
 
 	assert.Equal(t, 401, res.StatusCode)
 	assert.Equal(t, "application/json; charset=utf-8", res.Header.Get("Content-Type"))
-	assert.JSONEq(t, `{"error":{"status":"UNAUTHENTICATED","message":"test message with param"}}`, body)
+	assert.JSONEq(t, `{"error":{"status":"UNAUTHENTICATED","code":16,"message":"test message with param"}}`, body)
+}
+
+func TestErrorWithDetails(t *testing.T) {
+	err := ErrorWithDetails(InvalidArgument, []interface{}{map[string]string{"field": "who"}}, "bad input")
+
+	w := httptest.NewRecorder()
+	err.(RenderableError).Render(w)
+	res, body := response(w)
+
+	assert.Equal(t, 400, res.StatusCode)
+	assert.JSONEq(t, `{"error":{"status":"INVALID_ARGUMENT","code":3,"message":"bad input","details":[{"field":"who"}]}}`, body)
+}
+
+func TestWithRetryAfter(t *testing.T) {
+	err := WithRetryAfter(Error(ResourceExhausted, "slow down"), 30*time.Second)
+
+	w := httptest.NewRecorder()
+	err.(RenderableError).Render(w)
+	res, _ := response(w)
+
+	assert.Equal(t, 429, res.StatusCode)
+	assert.Equal(t, "30", res.Header.Get("Retry-After"))
+}
+
+func TestWithRetryAfter_OnlyForRetryableStatus(t *testing.T) {
+	err := WithRetryAfter(Error(InvalidArgument, "bad input"), 30*time.Second)
+
+	w := httptest.NewRecorder()
+	err.(RenderableError).Render(w)
+	res, _ := response(w)
+
+	assert.Empty(t, res.Header.Get("Retry-After"))
+}
+
+func TestWithCause_Unwrap(t *testing.T) {
+	cause := errors.New("underlying failure")
+	err := WithCause(Error(Internal, "wrapped"), cause)
+
+	assert.ErrorIs(t, err, cause)
+}
+
+type customRenderableError struct{}
+
+func (customRenderableError) Error() string { return "custom" }
+
+func (customRenderableError) Render(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusTeapot)
+	_, _ = w.Write([]byte(`{"error":{"status":"CUSTOM","code":99,"message":"custom"}}`))
+}
+
+func TestCallable_CustomRenderableError(t *testing.T) {
+	req := &testRequest{Who: "custom-error"}
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"custom-error"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	New(req).ServeHTTP(w, r)
+	res, body := response(w)
+
+	assert.Equal(t, http.StatusTeapot, res.StatusCode)
+	assert.JSONEq(t, `{"error":{"status":"CUSTOM","code":99,"message":"custom"}}`, body)
 }
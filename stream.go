@@ -0,0 +1,108 @@
+package callable
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// StreamingRequest is an optional extension of Request for callables that
+// stream intermediate results back to the client ahead of their final
+// result, mirroring Firebase's streaming callable responses. A Request
+// implementing this interface is still handled the regular, buffered way
+// for clients that don't ask for a stream.
+type StreamingRequest interface {
+	Request
+	// HandleStream is called instead of Handle when the client sends
+	// Accept: text/event-stream. Each call to send flushes a
+	// `{"message": chunk}` frame to the client immediately. The returned
+	// value becomes the call's final `{"result": ...}` frame.
+	HandleStream(ctx context.Context, call Call, send func(chunk interface{}) error) (interface{}, error)
+}
+
+func wantsStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+func (c *Callable) handleStream(ctx context.Context, w http.ResponseWriter, call Call, req StreamingRequest, logger logr.Logger, end EndFunc) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		if end != nil {
+			end(http.StatusInternalServerError, nil)
+		}
+		return Error(Internal, "streaming unsupported by response writer")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	send := func(chunk interface{}) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return writeSSE(w, flusher, struct {
+			Message interface{} `json:"message"`
+		}{chunk})
+	}
+
+	handle := HandleFunc(func(ctx context.Context, call Call) (interface{}, error) {
+		return req.HandleStream(ctx, call, send)
+	})
+	for i := len(c.callMiddleware) - 1; i >= 0; i-- {
+		handle = c.callMiddleware[i](handle)
+	}
+
+	result, err := handle(ctx, call)
+	if end != nil {
+		end(statusCodeFor(err), err)
+	}
+	if err != nil {
+		logger.Error(err, "callable returned error")
+		if werr := writeSSE(w, flusher, errorFrame(err)); werr != nil {
+			logger.Error(werr, "failed to write SSE error frame")
+		}
+		return nil
+	}
+
+	// Headers are already written by this point, so a write failure here
+	// (e.g. the client disconnected) can't be turned into a normal error
+	// response - log it and swallow it rather than letting it reach
+	// serveHTTP's fallback rendering, which would write a second status
+	// line and append a stray JSON blob after the SSE body.
+	if werr := writeSSE(w, flusher, resultFrame{result}); werr != nil {
+		logger.Error(werr, "failed to write SSE result frame")
+	}
+	return nil
+}
+
+type resultFrame struct {
+	Result interface{} `json:"result"`
+}
+
+func writeSSE(w http.ResponseWriter, flusher http.Flusher, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+func errorFrame(err error) errorResponse {
+	var cerr callError
+	if !errors.As(err, &cerr) {
+		cerr = newError(Internal, "%s", err.Error())
+	}
+	return cerr.payload()
+}
@@ -0,0 +1,121 @@
+package callable
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStreamingRequest struct {
+	testRequest
+}
+
+func (r *testStreamingRequest) HandleStream(ctx context.Context, call Call, send func(chunk interface{}) error) (interface{}, error) {
+	if r.Who == "error" {
+		return nil, Error(Internal, "stream failed")
+	}
+	if err := send("chunk1"); err != nil {
+		return nil, err
+	}
+	if err := send("chunk2"); err != nil {
+		return nil, err
+	}
+	return testResponse{"Hello " + r.Who + "!"}, nil
+}
+
+func TestCallable_Stream(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	New(&testStreamingRequest{}).ServeHTTP(w, r)
+	res, body := response(w)
+
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+	assert.Equal(t,
+		"data: {\"message\":\"chunk1\"}\n\n"+
+			"data: {\"message\":\"chunk2\"}\n\n"+
+			"data: {\"result\":{\"greeting\":\"Hello World!\"}}\n\n",
+		body)
+}
+
+func TestCallable_Stream_Error(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"error"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	New(&testStreamingRequest{}).ServeHTTP(w, r)
+	res, body := response(w)
+
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "data: {\"error\":{\"status\":\"INTERNAL\",\"code\":13,\"message\":\"stream failed\"}}\n\n", body)
+}
+
+func TestCallable_Stream_CallMiddleware(t *testing.T) {
+	var called bool
+	mw := func(next HandleFunc) HandleFunc {
+		return func(ctx context.Context, call Call) (interface{}, error) {
+			called = true
+			return nil, Error(PermissionDenied, "denied")
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	New(&testStreamingRequest{}, WithCallMiddleware(mw)).ServeHTTP(w, r)
+	res, body := response(w)
+
+	assert.True(t, called, "CallMiddleware should run for streaming requests too")
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "data: {\"error\":{\"status\":\"PERMISSION_DENIED\",\"code\":7,\"message\":\"denied\"}}\n\n", body)
+}
+
+// failingResponseWriter simulates a client that disconnects mid-stream:
+// every Write after headers are sent fails, as a real net.Conn write would.
+type failingResponseWriter struct {
+	http.ResponseWriter
+	headerWrites int
+}
+
+func (w *failingResponseWriter) WriteHeader(code int) {
+	w.headerWrites++
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *failingResponseWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write: broken pipe")
+}
+
+func (w *failingResponseWriter) Flush() {
+	w.ResponseWriter.(http.Flusher).Flush()
+}
+
+func TestCallable_Stream_WriteFailureDoesNotAppendFallbackError(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "text/event-stream")
+	w := &failingResponseWriter{ResponseWriter: httptest.NewRecorder()}
+	New(&testStreamingRequest{}).ServeHTTP(w, r)
+
+	assert.Equal(t, 1, w.headerWrites, "a write failure mid-stream must not trigger a second, fallback WriteHeader")
+}
+
+func TestCallable_Stream_FallsBackWithoutAcceptHeader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"data":{"who":"World"}}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	New(&testStreamingRequest{}).ServeHTTP(w, r)
+	res, body := response(w)
+
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "application/json; charset=utf-8", res.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"data":{"greeting":"Hello World!"}}`, body)
+}